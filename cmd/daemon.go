@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nmelo/gasadd/internal/audit"
+	"github.com/nmelo/gasadd/internal/health"
+	"github.com/nmelo/gasadd/internal/outbox"
+	"github.com/nmelo/gasadd/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	maxAgeFlag         time.Duration
+	heartbeatFlag      bool
+	heartbeatEveryFlag time.Duration
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Watch the outbox and deliver queued messages as targets go idle",
+	Long: `ga daemon watches the persistent outbox (see ga outbox) and delivers each
+queued message to its target as soon as that window stops showing pending
+input, retrying with exponential backoff until --max-age is reached.
+
+It also serves the control socket at $XDG_RUNTIME_DIR/gasadd.sock that
+"ga" and "ga outbox" use to push new items and query status. With
+--heartbeat it additionally samples liveness into the health state file.
+Run one daemon per machine; it exits when killed.`,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().DurationVar(&maxAgeFlag, "max-age", 24*time.Hour, "Give up on an item and mark it expired after this long")
+	daemonCmd.Flags().BoolVar(&heartbeatFlag, "heartbeat", false, "Also sample liveness for every window in every local tmux session")
+	daemonCmd.Flags().DurationVar(&heartbeatEveryFlag, "heartbeat-every", 10*time.Second, "How often to sample liveness when --heartbeat is set")
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	path, err := outbox.DefaultPath()
+	if err != nil {
+		return err
+	}
+	store, err := outbox.Open(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	logDir, err := audit.ResolveLogDir(logDirFlag)
+	if err != nil {
+		return err
+	}
+	logger, err := audit.NewFileLogger(logDir)
+	if err != nil {
+		return fmt.Errorf("setting up audit log: %w", err)
+	}
+
+	transport := tmux.NewLocalTransport()
+	d := &outbox.Daemon{
+		Store:     store,
+		Transport: transport,
+		MaxAge:    maxAgeFlag,
+		Logger:    logger,
+	}
+
+	if heartbeatFlag {
+		go runHeartbeatLoop(transport, heartbeatEveryFlag)
+	}
+
+	fmt.Printf("gasadd daemon watching outbox at %s\n", path)
+	return d.Run()
+}
+
+// runHeartbeatLoop periodically samples liveness for every window in
+// every local tmux session into the shared state file, so "ga health"
+// and --only-idle/--only-working/--require-alive see up-to-date data
+// even between ga invocations.
+func runHeartbeatLoop(transport tmux.Transport, every time.Duration) {
+	path, err := health.DefaultPath()
+	if err != nil {
+		log.Printf("heartbeat: %v", err)
+		return
+	}
+	store := health.Open(path)
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for range ticker.C {
+		sessions, err := tmux.ListSessions()
+		if err != nil {
+			log.Printf("heartbeat: listing sessions: %v", err)
+			continue
+		}
+
+		state, err := store.Load()
+		if err != nil {
+			log.Printf("heartbeat: loading state: %v", err)
+			continue
+		}
+
+		for _, session := range sessions {
+			windows, err := transport.ListWindows(session)
+			if err != nil {
+				log.Printf("heartbeat: listing windows in %s: %v", session, err)
+				continue
+			}
+			health.Sample(transport, session, windows, state)
+		}
+
+		if err := store.Save(state); err != nil {
+			log.Printf("heartbeat: saving state: %v", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nmelo/gasadd/internal/health"
+	"github.com/nmelo/gasadd/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Show liveness status for every window in a session",
+	Long: `ga health samples every window in a session (the current one by default,
+or -s/--session) and prints its liveness: whether it looks like it's
+working, waiting for input, or hasn't been observed yet, plus how long
+since it last produced output. The sample it takes is also recorded to
+the same state file --only-idle, --only-working, and --require-alive
+read from.`,
+	RunE: runHealth,
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	session := sessionFlag
+	if session == "" {
+		if !tmux.IsInsideTmux() {
+			return fmt.Errorf("not inside tmux; use -s/--session to specify a session")
+		}
+		var err error
+		session, _, _, err = tmux.GetCurrentContext()
+		if err != nil {
+			return fmt.Errorf("failed to get tmux context: %w", err)
+		}
+	}
+
+	transport := tmux.NewLocalTransport()
+	windows, err := transport.ListWindows(session)
+	if err != nil {
+		return fmt.Errorf("failed to list windows: %w", err)
+	}
+
+	path, err := health.DefaultPath()
+	if err != nil {
+		return err
+	}
+	store := health.Open(path)
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	// Snapshot the state as it stood before this invocation's own sample,
+	// so STATE/(stale) reflect the last heartbeat before now rather than
+	// the sample this call is about to take (see filterByHealth).
+	prev := make(map[string]health.Window, len(state))
+	for k, v := range state {
+		prev[k] = v
+	}
+
+	health.Sample(transport, session, windows, state)
+
+	if err := store.Save(state); err != nil {
+		return err
+	}
+
+	fmt.Printf("%-4s %-20s %-10s %-8s %s\n", "WIN", "NAME", "STATE", "CLAUDE", "LAST OUTPUT")
+	for _, w := range windows {
+		rec := prev[health.Key(session, w.Index)]
+		claude := ""
+		if tmux.IsClaudeRunning(transport, w) {
+			claude = "yes"
+		}
+		status := rec.State(workingWithinFlag)
+		if rec.Stale(staleAfterFlag) {
+			status += " (stale)"
+		}
+		ago := "never"
+		if !rec.LastOutputAt.IsZero() {
+			ago = time.Since(rec.LastOutputAt).Round(time.Second).String() + " ago"
+		}
+		fmt.Printf("%-4d %-20s %-10s %-8s %s\n", w.Index, w.Name, status, claude, ago)
+	}
+	return nil
+}
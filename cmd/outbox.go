@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nmelo/gasadd/internal/outbox"
+	"github.com/spf13/cobra"
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect and manage the persistent outbox",
+	Long: `ga outbox lists, flushes, and cancels messages queued for deferred
+delivery because their target had pending input when ga was run. These
+commands talk to the ga daemon over its control socket, so the daemon
+must be running.`,
+}
+
+var outboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every outbox item (pending, delivered, cancelled, or expired)",
+	RunE:  runOutboxList,
+}
+
+var outboxFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Retry delivery of every pending item now, ignoring backoff",
+	RunE:  runOutboxFlush,
+}
+
+var outboxCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Cancel a pending item so the daemon stops retrying it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOutboxCancel,
+}
+
+func init() {
+	outboxCmd.AddCommand(outboxListCmd, outboxFlushCmd, outboxCancelCmd)
+	rootCmd.AddCommand(outboxCmd)
+}
+
+func runOutboxList(cmd *cobra.Command, args []string) error {
+	if !outbox.Ping() {
+		return fmt.Errorf("ga daemon is not running; start it with `ga daemon`")
+	}
+	items, err := outbox.QueryList("")
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("outbox is empty")
+		return nil
+	}
+	for _, it := range items {
+		fmt.Printf("%d\t%s\t%s\tattempts=%d\tenqueued=%s\t%q\n",
+			it.ID, it.Status, it.Target, it.Attempts, it.EnqueuedAt.Format("2006-01-02T15:04:05"), it.Message)
+	}
+	return nil
+}
+
+func runOutboxFlush(cmd *cobra.Command, args []string) error {
+	if !outbox.Ping() {
+		return fmt.Errorf("ga daemon is not running; start it with `ga daemon`")
+	}
+	return outbox.SendFlush()
+}
+
+func runOutboxCancel(cmd *cobra.Command, args []string) error {
+	if !outbox.Ping() {
+		return fmt.Errorf("ga daemon is not running; start it with `ga daemon`")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid outbox id %q: %w", args[0], err)
+	}
+	return outbox.SendCancel(id)
+}
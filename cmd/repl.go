@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/nmelo/gasadd/internal/lock"
+	"github.com/nmelo/gasadd/internal/tmux"
+	"golang.org/x/term"
+)
+
+// replState holds the sticky filter a REPL session builds up with
+// `target`, `dry`, and `force`, so the operator doesn't have to re-type
+// flags for every line sent.
+type replState struct {
+	session            string
+	currentWindowIndex int // this REPL's own window: marked with * in `list`, always excluded from sends
+	lastTargetIndex    int // window index last sent to, shown with a marker in `list`
+
+	names   []string // sticky window/index filter set by `target -w ...`
+	pattern string   // sticky glob filter set by `target <glob>`
+	dryRun  bool
+	force   bool
+}
+
+// runREPL launches the interactive shell used when ga is invoked with no
+// arguments from a terminal. It targets the local tmux server in the
+// caller's current session, or in the -s/--session one when run outside
+// tmux.
+func runREPL() error {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("putting terminal in raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	screen := struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+	t := term.NewTerminal(screen, "> ")
+
+	if w, h, err := term.GetSize(fd); err == nil {
+		t.SetSize(w, h)
+	}
+
+	sigwinch := make(chan os.Signal, 1)
+	signal.Notify(sigwinch, syscall.SIGWINCH)
+	defer signal.Stop(sigwinch)
+	go func() {
+		for range sigwinch {
+			if w, h, err := term.GetSize(fd); err == nil {
+				t.SetSize(w, h)
+			}
+		}
+	}()
+
+	state := &replState{lastTargetIndex: -1}
+	if tmux.IsInsideTmux() {
+		session, idx, _, err := tmux.GetCurrentContext()
+		if err != nil {
+			return fmt.Errorf("failed to get tmux context: %w", err)
+		}
+		state.session = session
+		state.currentWindowIndex = idx
+	} else if sessionFlag != "" {
+		state.session = sessionFlag
+		state.currentWindowIndex = -1
+	} else {
+		return fmt.Errorf("not inside tmux; use -s/--session to start the REPL against a specific session")
+	}
+
+	transport := tmux.NewLocalTransport()
+
+	fmt.Fprintln(t, "gasadd interactive mode — type `list` to see windows, `help` for commands, Ctrl-D to quit")
+	for {
+		line, err := t.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := state.dispatch(t, transport, line); err != nil {
+			fmt.Fprintf(t, "error: %v\n", err)
+		}
+	}
+}
+
+func (s *replState) dispatch(t *term.Terminal, transport tmux.Transport, line string) error {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "help":
+		fmt.Fprint(t, `commands:
+  list                 show windows matching the filter, [claude] tags Claude agents,
+                       markers: * = this window, > = last target
+  target <glob>        set a sticky glob filter on window names
+  target -w <name>     set a sticky filter to one window by name or index
+  target clear         clear the sticky filter
+  dry                  toggle dry-run
+  force on|off         toggle sending even when the target is typing
+  peek <win>           dump recent output from a window
+  <anything else>      sent as a message to the currently selected targets
+`)
+		return nil
+
+	case "list":
+		return s.cmdList(t, transport)
+
+	case "target":
+		return s.cmdTarget(t, fields[1:])
+
+	case "dry":
+		s.dryRun = !s.dryRun
+		fmt.Fprintf(t, "dry-run %s\n", onOff(s.dryRun))
+		return nil
+
+	case "force":
+		if len(fields) != 2 || (fields[1] != "on" && fields[1] != "off") {
+			return fmt.Errorf("usage: force on|off")
+		}
+		s.force = fields[1] == "on"
+		fmt.Fprintf(t, "force %s\n", onOff(s.force))
+		return nil
+
+	case "peek":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: peek <window>")
+		}
+		return s.cmdPeek(t, transport, fields[1])
+
+	default:
+		return s.send(t, transport, line)
+	}
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+func (s *replState) cmdList(t *term.Terminal, transport tmux.Transport) error {
+	windows, err := transport.ListWindows(s.session)
+	if err != nil {
+		return err
+	}
+	targets := filterWindows(windows, -1, s.names, s.pattern, true)
+	for _, w := range targets {
+		marker := "  "
+		if w.Index == s.currentWindowIndex {
+			marker = "* "
+		} else if w.Index == s.lastTargetIndex {
+			marker = "> "
+		}
+		claudeStatus := ""
+		if tmux.IsClaudeRunning(transport, w) {
+			claudeStatus = " [claude]"
+		}
+		fmt.Fprintf(t, "%s%d: %s (%s)%s\n", marker, w.Index, w.Name, w.Command, claudeStatus)
+	}
+	return nil
+}
+
+func (s *replState) cmdTarget(t *term.Terminal, args []string) error {
+	switch {
+	case len(args) == 0:
+		return fmt.Errorf("usage: target <glob> | target -w <name> | target clear")
+	case args[0] == "clear":
+		s.names = nil
+		s.pattern = ""
+		fmt.Fprintln(t, "target filter cleared")
+	case args[0] == "-w":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: target -w <name>")
+		}
+		s.names = []string{args[1]}
+		s.pattern = ""
+		fmt.Fprintf(t, "targeting window %q\n", args[1])
+	default:
+		s.names = nil
+		s.pattern = args[0]
+		fmt.Fprintf(t, "targeting windows matching %q\n", args[0])
+	}
+	return nil
+}
+
+func (s *replState) cmdPeek(t *term.Terminal, transport tmux.Transport, win string) error {
+	windows, err := transport.ListWindows(s.session)
+	if err != nil {
+		return err
+	}
+	for _, w := range windows {
+		if w.Name != win && fmt.Sprintf("%d", w.Index) != win {
+			continue
+		}
+		out, err := transport.CapturePane(fmt.Sprintf("%s:%d", s.session, w.Index), 40)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(t, out)
+		return nil
+	}
+	return fmt.Errorf("no window named %q", win)
+}
+
+func (s *replState) send(t *term.Terminal, transport tmux.Transport, message string) error {
+	windows, err := transport.ListWindows(s.session)
+	if err != nil {
+		return err
+	}
+	targets := filterWindows(windows, s.currentWindowIndex, s.names, s.pattern, false)
+	if len(targets) == 0 {
+		fmt.Fprintln(t, "no windows match the current target filter")
+		return nil
+	}
+
+	for _, w := range targets {
+		if !tmux.IsClaudeRunning(transport, w) {
+			fmt.Fprintf(t, "skipping %q: no Claude agent running\n", w.Name)
+			continue
+		}
+		target := fmt.Sprintf("%s:%d", s.session, w.Index)
+		if s.dryRun {
+			fmt.Fprintf(t, "would send to %s: %s\n", target, message)
+			continue
+		}
+
+		// Serialize the check-and-send sequence against a concurrent
+		// ga/gn/gp invocation targeting the same local pane.
+		fl, err := lock.Target(s.session, w.Index, lock.DefaultTimeout)
+		if err != nil {
+			fmt.Fprintf(t, "skipping %q: %v\n", w.Name, err)
+			continue
+		}
+
+		if !s.force {
+			if hasPending, _ := transport.HasPendingInput(target); hasPending {
+				fmt.Fprintf(t, "skipping %q: busy (user is typing)\n", w.Name)
+				fl.Unlock()
+				continue
+			}
+		}
+		if err := transport.AddMessage(target, message); err != nil {
+			fmt.Fprintf(t, "failed to send to %s: %v\n", w.Name, err)
+			fl.Unlock()
+			continue
+		}
+		fl.Unlock()
+		s.lastTargetIndex = w.Index
+		fmt.Fprintf(t, "sent to %s\n", target)
+	}
+	return nil
+}
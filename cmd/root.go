@@ -1,23 +1,42 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/nmelo/gasadd/internal/audit"
+	"github.com/nmelo/gasadd/internal/health"
+	"github.com/nmelo/gasadd/internal/lock"
+	"github.com/nmelo/gasadd/internal/outbox"
 	"github.com/nmelo/gasadd/internal/tmux"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	windowFlags []string
-	sessionFlag string
-	patternFlag string
-	anyFlag     bool
-	allFlag     bool
-	dryRunFlag  bool
-	forceFlag   bool
+	windowFlags  []string
+	sessionFlag  string
+	patternFlag  string
+	anyFlag      bool
+	allFlag      bool
+	dryRunFlag   bool
+	forceFlag    bool
+	hostFlag     string
+	identityFlag string
+	jumpFlag     string
+	fleetFlag    string
+	outputFlag   string
+	logDirFlag   string
+	lockTimeout  time.Duration
+
+	onlyIdleFlag      bool
+	onlyWorkingFlag   bool
+	requireAliveFlag  bool
+	workingWithinFlag time.Duration
+	staleAfterFlag    time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -58,12 +77,56 @@ EXAMPLES:
   ga -a "note to self"                   # Include own window
   ga -n "test"                           # Dry-run: show targets
   ga -f -w worker-1 "urgent"             # Force send even if user is typing
+  ga --host user@box "status?"           # Target Claude windows on a remote host over SSH
+  ga -F mycluster "checkpoint"           # Fan out to every host in the "mycluster" fleet
+
+REMOTE TARGETING:
+  --host and -F talk to tmux over SSH using the same session/window/pattern
+  filters as local targeting. -F reads named fleets from
+  $XDG_CONFIG_HOME/gasadd/fleets.json and sends to every host in the fleet.
+
+INTERACTIVE MODE:
+  Running "ga" with no message from a terminal drops into a REPL with
+  sticky targeting ("target <glob>", "dry", "force on/off") so long
+  coordination sessions don't need every flag re-typed on each line.
+
+DEFERRED DELIVERY:
+  Messages that can't be sent because the target is busy (user is typing)
+  are dropped unless "ga daemon" is running, in which case they're queued
+  to a persistent outbox and delivered as soon as the target goes idle.
+  This applies to local targets only; busy --host/-F targets are always
+  dropped. See "ga daemon" and "ga outbox".
+
+CONCURRENCY:
+  Each local target is protected by an advisory lock at
+  $XDG_RUNTIME_DIR/gasadd/locks/<session>-<window>.lock for the duration
+  of the pending-input check and send, so two concurrent ga invocations
+  (e.g. a human and a cron job) can't interleave keystrokes into the same
+  pane. Tune how long to wait for a busy lock with --lock-timeout.
+
+LIVENESS:
+  Each invocation samples pane output and records it to
+  $XDG_STATE_HOME/gasadd/state.json (or run "ga daemon --heartbeat" to
+  sample continuously in the background). --only-idle skips windows that
+  have produced output recently (i.e. are actively working); --only-working
+  is the inverse; --require-alive skips windows with no recent heartbeat
+  sample. Sampling and these filters apply to local targets only; they
+  are ignored for --host/-F. See "ga health" to inspect the table directly.
+
+MACHINE-READABLE OUTPUT AND AUDIT LOG:
+  --output json emits one newline-delimited JSON object per target plus a
+  final summary object, instead of the human-readable text above. Every
+  invocation (in either output mode) also appends the same records to
+  $GASADD_LOG_DIR or $XDG_STATE_HOME/gasadd/logs/YYYY-MM-DD.jsonl (override
+  with --log-dir), so a supervising agent can tail the log to reconstruct
+  exactly which messages were delivered to which pane and when. Neither
+  applies to --dry-run, which only prints the would-be targets as text.
 
 RELATED TOOLS:
   gn (gasnudge) - Interrupt agents urgently (sends Escape + Enter)
   gp (gaspeek)  - Read output from agent windows
   gm (gasmail)  - Persistent messaging via beads database`,
-	Args: cobra.MinimumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 	RunE: runAdd,
 }
 
@@ -73,32 +136,120 @@ func Execute() error {
 
 func init() {
 	rootCmd.Flags().StringArrayVarP(&windowFlags, "window", "w", nil, "Target specific window(s) by name (repeatable)")
-	rootCmd.Flags().StringVarP(&sessionFlag, "session", "s", "", "Target session (default: current)")
+	rootCmd.PersistentFlags().StringVarP(&sessionFlag, "session", "s", "", "Target session (default: current)")
 	rootCmd.Flags().StringVarP(&patternFlag, "pattern", "p", "", "Filter windows by name pattern (glob-style)")
 	rootCmd.Flags().BoolVar(&anyFlag, "any", false, "Include non-Claude windows (default: Claude only)")
 	rootCmd.Flags().BoolVarP(&allFlag, "all", "a", false, "Include current window (default: exclude self)")
 	rootCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Show what would receive the message")
 	rootCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Send even if target has pending input")
+	rootCmd.Flags().StringVar(&hostFlag, "host", "", "Target a remote host over SSH (user@box)")
+	rootCmd.Flags().StringVar(&identityFlag, "identity", "", "SSH private key to use with --host/--jump (default: SSH_AUTH_SOCK)")
+	rootCmd.Flags().StringVar(&jumpFlag, "jump", "", "ProxyJump host for --host (user@box)")
+	rootCmd.Flags().StringVarP(&fleetFlag, "fleet", "F", "", "Fan out to every host in the named fleet (see $XDG_CONFIG_HOME/gasadd/fleets.json)")
+	rootCmd.Flags().StringVar(&outputFlag, "output", "text", "Output format: text or json")
+	rootCmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Directory for the structured audit log (default: $GASADD_LOG_DIR or $XDG_STATE_HOME/gasadd/logs)")
+	rootCmd.Flags().DurationVar(&lockTimeout, "lock-timeout", lock.DefaultTimeout, "How long to wait for another ga/gn/gp invocation to finish with a target before giving up")
+	rootCmd.Flags().BoolVar(&onlyIdleFlag, "only-idle", false, "Skip windows that have produced output recently (i.e. are actively working)")
+	rootCmd.Flags().BoolVar(&onlyWorkingFlag, "only-working", false, "Skip windows that haven't produced output recently (inverse of --only-idle)")
+	rootCmd.Flags().BoolVar(&requireAliveFlag, "require-alive", false, "Skip windows whose heartbeat hasn't been sampled recently")
+	rootCmd.PersistentFlags().DurationVar(&workingWithinFlag, "working-within", 10*time.Second, "How recently a window must have output to count as working, for --only-idle/--only-working")
+	rootCmd.PersistentFlags().DurationVar(&staleAfterFlag, "stale-after", time.Minute, "How long without a heartbeat sample before a window counts as stale, for --require-alive")
+}
+
+// endpoint pairs a Transport with the session it should be queried
+// against, so the same filter-and-send logic in runAdd can run once per
+// host whether targeting is local, a single --host, or a whole -F fleet.
+type endpoint struct {
+	label     string // host identifier used in status output, "" for local
+	transport tmux.Transport
+}
+
+// resolveEndpoints builds the list of transports to send to based on
+// --host, --jump, --identity, and -F. With none of those set it returns a
+// single local endpoint.
+func resolveEndpoints() ([]endpoint, error) {
+	if fleetFlag != "" && hostFlag != "" {
+		return nil, fmt.Errorf("--fleet and --host are mutually exclusive")
+	}
+
+	if fleetFlag != "" {
+		fleet, err := tmux.LoadFleet(fleetFlag)
+		if err != nil {
+			return nil, err
+		}
+		var endpoints []endpoint
+		for _, spec := range fleet.Hosts {
+			t, err := tmux.NewSSHTransport(spec)
+			if err != nil {
+				return nil, fmt.Errorf("connecting to %s: %w", spec.Host, err)
+			}
+			endpoints = append(endpoints, endpoint{label: spec.Host, transport: t})
+		}
+		return endpoints, nil
+	}
+
+	if hostFlag != "" {
+		t, err := tmux.NewSSHTransport(tmux.HostSpec{Host: hostFlag, Identity: identityFlag, Jump: jumpFlag})
+		if err != nil {
+			return nil, fmt.Errorf("connecting to %s: %w", hostFlag, err)
+		}
+		return []endpoint{{label: hostFlag, transport: t}}, nil
+	}
+
+	return []endpoint{{label: "", transport: tmux.NewLocalTransport()}}, nil
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			return runREPL()
+		}
+		return fmt.Errorf("requires at least 1 arg(s), only received 0")
+	}
+
+	if outputFlag != "text" && outputFlag != "json" {
+		return fmt.Errorf("invalid --output %q: must be \"text\" or \"json\"", outputFlag)
+	}
+	jsonMode := outputFlag == "json"
+
 	message := strings.Join(args, " ")
+	msgHash := audit.MessageHash(message)
+
+	var logger *audit.FileLogger
+	if !dryRunFlag {
+		logDir, err := audit.ResolveLogDir(logDirFlag)
+		if err != nil {
+			return err
+		}
+		logger, err = audit.NewFileLogger(logDir)
+		if err != nil {
+			return fmt.Errorf("setting up audit log: %w", err)
+		}
+	}
+
+	endpoints, err := resolveEndpoints()
+	if err != nil {
+		return err
+	}
+	for _, ep := range endpoints {
+		if closer, ok := ep.transport.(interface{ Close() error }); ok {
+			defer closer.Close()
+		}
+	}
 
-	// Determine session
+	// Determine session and, for local targeting, the window to exclude
+	// by default (the caller's own window).
 	var session string
-	var currentWindowIndex int
-	var currentPaneID string
+	currentWindowIndex := -1
+	remote := hostFlag != "" || fleetFlag != ""
 
-	if tmux.IsInsideTmux() {
-		var err error
-		currentSession, currentWindowIdx, paneID, err := tmux.GetCurrentContext()
+	if !remote && tmux.IsInsideTmux() {
+		currentSession, currentWindowIdx, _, err := tmux.GetCurrentContext()
 		if err != nil {
 			return fmt.Errorf("failed to get tmux context: %w", err)
 		}
-		currentPaneID = paneID
 		if sessionFlag != "" {
 			session = sessionFlag
-			currentWindowIndex = -1 // Different session, don't exclude any window
 		} else {
 			session = currentSession
 			currentWindowIndex = currentWindowIdx
@@ -108,32 +259,94 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("not inside tmux; use -s/--session to specify target session")
 		}
 		session = sessionFlag
-		currentWindowIndex = -1 // No window to exclude
 	}
 
-	// Verify session exists
-	if !tmux.SessionExists(session) {
-		return fmt.Errorf("session %q does not exist", session)
+	var succeeded, failed, skippedTyping, skippedNoClaude int
+	for _, ep := range endpoints {
+		s, f, st, sn, err := sendToEndpoint(ep, session, currentWindowIndex, message, jsonMode, logger, msgHash)
+		if err != nil {
+			return err
+		}
+		succeeded += s
+		failed += f
+		skippedTyping += st
+		skippedNoClaude += sn
+	}
+
+	if dryRunFlag {
+		return nil
 	}
 
-	// Get all windows
-	windows, err := tmux.ListWindows(session)
-	if err != nil {
-		return fmt.Errorf("failed to list windows: %w", err)
+	// The summary is logged to the audit file for every non-dry-run
+	// invocation, regardless of stdout format, so a supervising agent tailing the log
+	// sees one per invocation rather than only for --output json runs.
+	summary := audit.Summary{
+		Timestamp:       time.Now(),
+		Session:         session,
+		Queued:          succeeded,
+		SkippedTyping:   skippedTyping,
+		SkippedNoClaude: skippedNoClaude,
+		Failed:          failed,
+	}
+	if logger != nil {
+		if err := logger.Append(summary); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to write audit log: %v\n", err)
+		}
+	}
+
+	if jsonMode {
+		emitJSON(summary)
+		if failed > 0 {
+			return fmt.Errorf("%d message(s) failed", failed)
+		}
+		return nil
+	}
+
+	skipped := skippedTyping + skippedNoClaude
+	if failed > 0 || skipped > 0 {
+		var parts []string
+		if succeeded > 0 {
+			parts = append(parts, fmt.Sprintf("queued to %d", succeeded))
+		}
+		if skippedNoClaude > 0 {
+			parts = append(parts, fmt.Sprintf("%d skipped (no Claude)", skippedNoClaude))
+		}
+		if skippedTyping > 0 {
+			parts = append(parts, fmt.Sprintf("%d deferred (user typing)", skippedTyping))
+		}
+		if failed > 0 {
+			parts = append(parts, fmt.Sprintf("%d failed", failed))
+		}
+		fmt.Printf("%s\n", strings.Join(parts, ", "))
+		if failed > 0 {
+			return fmt.Errorf("%d message(s) failed", failed)
+		}
+		return nil
 	}
 
-	// Filter windows
+	fmt.Printf("Queued to %d window(s)\n", succeeded)
+	return nil
+}
+
+func emitJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	_ = enc.Encode(v)
+}
+
+// filterWindows narrows windows down to the ones that should receive a
+// message: the caller's own window is excluded unless allFlag is set,
+// then an explicit name/index list and a glob pattern are applied, each
+// only when non-empty. Shared by runAdd and the interactive REPL's
+// "list" command and message sends.
+func filterWindows(windows []tmux.Window, currentWindowIndex int, names []string, pattern string, allFlag bool) []tmux.Window {
 	var targets []tmux.Window
 	for _, w := range windows {
-		// Exclude current window unless --all is set
 		if !allFlag && currentWindowIndex >= 0 && w.Index == currentWindowIndex {
 			continue
 		}
-
-		// Filter by specific window names if provided
-		if len(windowFlags) > 0 {
+		if len(names) > 0 {
 			found := false
-			for _, name := range windowFlags {
+			for _, name := range names {
 				if w.Name == name || fmt.Sprintf("%d", w.Index) == name {
 					found = true
 					break
@@ -143,54 +356,190 @@ func runAdd(cmd *cobra.Command, args []string) error {
 				continue
 			}
 		}
+		if pattern != "" && !tmux.MatchPattern(w.Name, pattern) {
+			continue
+		}
+		targets = append(targets, w)
+	}
+	return targets
+}
+
+// filterByHealth samples liveness for targets (always, as a side effect,
+// so `ga health` and other invocations see fresh data) and then, if
+// --only-idle, --only-working, or --require-alive is set, narrows targets
+// down further based on the resulting state.
+func filterByHealth(transport tmux.Transport, session string, targets []tmux.Window) ([]tmux.Window, error) {
+	path, err := health.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	store := health.Open(path)
+	state, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot the state as it stood before this invocation's own sample.
+	// --require-alive is meant to catch a window nobody has heartbeated
+	// in a while; evaluating it against the sample this call is about to
+	// take would make every window look alive, since it was just checked.
+	prev := make(map[string]health.Window, len(state))
+	for k, v := range state {
+		prev[k] = v
+	}
+
+	health.Sample(transport, session, targets, state)
 
-		// Filter by pattern if provided
-		if patternFlag != "" && !tmux.MatchPattern(w.Name, patternFlag) {
+	if err := store.Save(state); err != nil {
+		return nil, err
+	}
+
+	if !onlyIdleFlag && !onlyWorkingFlag && !requireAliveFlag {
+		return targets, nil
+	}
+
+	var filtered []tmux.Window
+	for _, w := range targets {
+		rec := prev[health.Key(session, w.Index)]
+		if requireAliveFlag && rec.Stale(staleAfterFlag) {
 			continue
 		}
+		switch rec.State(workingWithinFlag) {
+		case health.StateWorking:
+			if onlyIdleFlag {
+				continue
+			}
+		case health.StateWaiting:
+			if onlyWorkingFlag {
+				continue
+			}
+		case health.StateUnknown:
+			if onlyIdleFlag || onlyWorkingFlag {
+				continue
+			}
+		}
+		filtered = append(filtered, w)
+	}
+	return filtered, nil
+}
 
-		targets = append(targets, w)
+// sendToEndpoint filters the windows of session on ep down to the
+// requested targets and, unless this is a dry run, queues message to each
+// one. currentWindowIndex is the caller's own window index to exclude
+// (-1 for remote endpoints, where there is no "own window").
+func sendToEndpoint(ep endpoint, session string, currentWindowIndex int, message string, jsonMode bool, logger *audit.FileLogger, msgHash string) (succeeded, failed, skippedTyping, skippedNoClaude int, err error) {
+	prefix := ""
+	if ep.label != "" {
+		prefix = ep.label + ": "
+	}
+
+	if !ep.transport.SessionExists(session) {
+		return 0, 0, 0, 0, fmt.Errorf("%ssession %q does not exist", prefix, session)
+	}
+
+	windows, err := ep.transport.ListWindows(session)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("%sfailed to list windows: %w", prefix, err)
+	}
+
+	targets := filterWindows(windows, currentWindowIndex, windowFlags, patternFlag, allFlag)
+
+	// Heartbeat sampling only applies to the local tmux server; remote
+	// endpoints have no shared state file to sample into.
+	if ep.label == "" {
+		targets, err = filterByHealth(ep.transport, session, targets)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
 	}
 
 	if len(targets) == 0 {
-		fmt.Fprintln(os.Stderr, "No windows to send message to")
-		return nil
+		fmt.Fprintf(os.Stderr, "%sNo windows to send message to\n", prefix)
+		return 0, 0, 0, 0, nil
 	}
 
-	// Dry run: show targets and exit
 	if dryRunFlag {
-		fmt.Printf("Would queue message to %d window(s) in session %q:\n", len(targets), session)
+		fmt.Printf("Would queue message to %d window(s) in %ssession %q:\n", len(targets), prefix, session)
 		for _, w := range targets {
 			claudeStatus := ""
-			if tmux.IsClaudeRunning(w) {
+			if tmux.IsClaudeRunning(ep.transport, w) {
 				claudeStatus = " [claude]"
 			}
 			fmt.Printf("  %d: %s (%s)%s\n", w.Index, w.Name, w.Command, claudeStatus)
 		}
 		fmt.Printf("\nMessage: %s\n", message)
-		return nil
+		return 0, 0, 0, 0, nil
 	}
 
-	// Execute adds
-	var succeeded, failed, skippedTyping, skippedNoClaude int
-	for _, w := range targets {
+	record := func(w tmux.Window, action string, pendingInput bool, attempts int, errMsg string) {
+		if !jsonMode && logger == nil {
+			return
+		}
+		rec := audit.Record{
+			Timestamp:      time.Now(),
+			Session:        session,
+			WindowIndex:    w.Index,
+			WindowName:     w.Name,
+			Command:        w.Command,
+			ClaudeDetected: tmux.IsClaudeRunning(ep.transport, w),
+			PendingInput:   pendingInput,
+			Action:         action,
+			Error:          errMsg,
+			Attempts:       attempts,
+			MessageSHA256:  msgHash,
+		}
+		if jsonMode {
+			emitJSON(rec)
+		}
+		if logger != nil {
+			if err := logger.Append(rec); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to write audit log: %v\n", err)
+			}
+		}
+	}
+
+	// sendOne runs the full check-and-send sequence for a single target. It
+	// is a closure (rather than inline loop body) so a lock acquired for
+	// one target is released via defer as soon as that target is done,
+	// instead of being held until every target in this endpoint has been
+	// processed.
+	sendOne := func(w tmux.Window) {
 		target := fmt.Sprintf("%s:%d", session, w.Index)
 
-		// Verify Claude is running in the target window
-		if !anyFlag && !tmux.IsClaudeRunning(w) {
-			fmt.Fprintf(os.Stderr, "destination window %q has no Claude agent running - start Claude there first, or use --any to send anyway\n", w.Name)
+		if !anyFlag && !tmux.IsClaudeRunning(ep.transport, w) {
+			if !jsonMode {
+				fmt.Fprintf(os.Stderr, "%sdestination window %q has no Claude agent running - start Claude there first, or use --any to send anyway\n", prefix, w.Name)
+			}
+			record(w, audit.ActionSkippedNoClaude, false, 0, "")
 			skippedNoClaude++
-			continue
+			return
+		}
+
+		// Serialize the retry-check-and-send sequence against concurrent
+		// ga/gn/gp invocations targeting the same local pane; remote
+		// endpoints have no shared lock namespace to serialize against.
+		if ep.label == "" {
+			fl, err := lock.Target(session, w.Index, lockTimeout)
+			if err != nil {
+				if !jsonMode {
+					fmt.Fprintf(os.Stderr, "%sdestination window %q: %v\n", prefix, w.Name, err)
+				}
+				record(w, audit.ActionFailed, false, 0, err.Error())
+				failed++
+				return
+			}
+			defer fl.Unlock()
 		}
 
-		// Check for pending input (user is typing) unless --force is set
 		if !forceFlag {
 			var hasPending bool
+			var attempts int
 			const maxRetries = 3
 			const retryDelay = 5 * time.Second
 
 			for attempt := 0; attempt < maxRetries; attempt++ {
-				hasPending, _ = tmux.HasPendingInput(target)
+				attempts = attempt + 1
+				hasPending, _ = ep.transport.HasPendingInput(target)
 				if !hasPending {
 					break
 				}
@@ -200,45 +549,49 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			}
 
 			if hasPending {
-				fmt.Fprintf(os.Stderr, "destination window %q is busy (user is typing) - use --force if your message takes priority, or wait a few seconds and retry\n", w.Name)
+				if ep.label == "" && outbox.Ping() {
+					if err := outbox.SendEnqueue(target, session, w.Index, message); err != nil {
+						if !jsonMode {
+							fmt.Fprintf(os.Stderr, "%sdestination window %q is busy (user is typing) and enqueueing to the daemon failed: %v\n", prefix, w.Name, err)
+						}
+						record(w, audit.ActionSkippedTyping, true, attempts, err.Error())
+						skippedTyping++
+						return
+					}
+					if !jsonMode {
+						fmt.Fprintf(os.Stderr, "%sdestination window %q is busy (user is typing) - deferred to outbox for delivery once idle\n", prefix, w.Name)
+					}
+					record(w, audit.ActionDeferred, true, attempts, "")
+					skippedTyping++
+					return
+				}
+				if !jsonMode {
+					fmt.Fprintf(os.Stderr, "%sdestination window %q is busy (user is typing) - use --force if your message takes priority, or wait a few seconds and retry\n", prefix, w.Name)
+					if ep.label == "" {
+						fmt.Fprintln(os.Stderr, "(run `ga daemon` to defer busy messages to the outbox instead of dropping them)")
+					}
+				}
+				record(w, audit.ActionSkippedTyping, true, attempts, "")
 				skippedTyping++
-				continue
+				return
 			}
 		}
 
-		if err := tmux.AddMessage(target, message); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to queue message to %s: %v\n", w.Name, err)
+		if err := ep.transport.AddMessage(target, message); err != nil {
+			if !jsonMode {
+				fmt.Fprintf(os.Stderr, "%sFailed to queue message to %s: %v\n", prefix, w.Name, err)
+			}
+			record(w, audit.ActionFailed, false, 0, err.Error())
 			failed++
 		} else {
+			record(w, audit.ActionQueued, false, 0, "")
 			succeeded++
 		}
 	}
 
-	// Report results
-	_ = currentPaneID // unused but kept for future use
-
-	skipped := skippedTyping + skippedNoClaude
-	if failed > 0 || skipped > 0 {
-		var parts []string
-		if succeeded > 0 {
-			parts = append(parts, fmt.Sprintf("queued to %d", succeeded))
-		}
-		if skippedNoClaude > 0 {
-			parts = append(parts, fmt.Sprintf("%d skipped (no Claude)", skippedNoClaude))
-		}
-		if skippedTyping > 0 {
-			parts = append(parts, fmt.Sprintf("%d deferred (user typing)", skippedTyping))
-		}
-		if failed > 0 {
-			parts = append(parts, fmt.Sprintf("%d failed", failed))
-		}
-		fmt.Printf("%s\n", strings.Join(parts, ", "))
-		if failed > 0 {
-			return fmt.Errorf("%d message(s) failed", failed)
-		}
-		return nil
+	for _, w := range targets {
+		sendOne(w)
 	}
 
-	fmt.Printf("Queued to %d window(s)\n", succeeded)
-	return nil
+	return succeeded, failed, skippedTyping, skippedNoClaude, nil
 }
@@ -0,0 +1,131 @@
+// Package audit defines the structured record ga emits for each target it
+// processes, used both for --output json on stdout and for the
+// always-on append-only log under $XDG_STATE_HOME/gasadd/logs (or
+// --log-dir / $GASADD_LOG_DIR), so a supervising agent can reconstruct
+// exactly which coordination messages reached which pane and when.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Action values a Record's Action field can hold.
+const (
+	ActionQueued          = "queued"
+	ActionSkippedTyping   = "skipped_typing"
+	ActionSkippedNoClaude = "skipped_no_claude"
+	ActionFailed          = "failed"
+	// ActionDeferred marks a message handed off to the outbox instead of
+	// being dropped: still busy, but `ga daemon` will keep retrying it
+	// until it's delivered or it expires. Distinct from
+	// ActionSkippedTyping so a reader of the log can tell "will still
+	// arrive" from "nobody will ever deliver this".
+	ActionDeferred = "deferred_to_outbox"
+)
+
+// Record describes the outcome of trying to deliver a message to one
+// target window.
+type Record struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Session        string    `json:"session"`
+	WindowIndex    int       `json:"window_index"`
+	WindowName     string    `json:"window_name"`
+	Command        string    `json:"command"`
+	ClaudeDetected bool      `json:"claude_detected"`
+	PendingInput   bool      `json:"pending_input"`
+	Action         string    `json:"action"`
+	Error          string    `json:"error,omitempty"`
+	Attempts       int       `json:"attempts"`
+	MessageSHA256  string    `json:"message_sha256"`
+}
+
+// Summary is emitted once per invocation after every target's Record.
+type Summary struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Session         string    `json:"session"`
+	Queued          int       `json:"queued"`
+	SkippedTyping   int       `json:"skipped_typing"`
+	SkippedNoClaude int       `json:"skipped_no_claude"`
+	Failed          int       `json:"failed"`
+}
+
+// MessageHash returns the hex-encoded SHA-256 of message, used so audit
+// records can correlate deliveries without persisting message contents
+// the caller may consider sensitive.
+func MessageHash(message string) string {
+	sum := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(sum[:])
+}
+
+// FileLogger appends Records and Summaries as newline-delimited JSON to
+// $dir/YYYY-MM-DD.jsonl. Each Append opens the file with O_APPEND and
+// holds an advisory flock for the duration of the write, so concurrent ga
+// invocations don't interleave partial lines.
+type FileLogger struct {
+	dir string
+}
+
+// NewFileLogger creates dir if necessary and returns a logger that writes
+// into it.
+func NewFileLogger(dir string) (*FileLogger, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating log dir: %w", err)
+	}
+	return &FileLogger{dir: dir}, nil
+}
+
+// ResolveLogDir picks the log directory to use: an explicit --log-dir
+// flag value, then $GASADD_LOG_DIR, then the default under
+// $XDG_STATE_HOME/gasadd/logs.
+func ResolveLogDir(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if env := os.Getenv("GASADD_LOG_DIR"); env != "" {
+		return env, nil
+	}
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gasadd", "logs"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "gasadd", "logs"), nil
+}
+
+func (l *FileLogger) path(now time.Time) string {
+	return filepath.Join(l.dir, now.Format("2006-01-02")+".jsonl")
+}
+
+// Append marshals v to a single JSON line and appends it to today's log
+// file under an exclusive advisory lock.
+func (l *FileLogger) Append(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling audit record: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path(time.Now()), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("locking audit log: %w", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package health tracks liveness for tmux windows running Claude agents:
+// when each one last produced output, and whether it looks like it's
+// still working or has gone idle waiting for input. It turns the boolean
+// IsClaudeRunning check into a richer status model so ga can target the
+// right subset of a swarm with --only-idle, --only-working, and
+// --require-alive.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nmelo/gasadd/internal/tmux"
+)
+
+// State values summarizing what a window appears to be doing.
+const (
+	StateWorking = "working" // pane output changed recently
+	StateWaiting = "waiting" // no recent output; presumably waiting for input
+	StateUnknown = "unknown" // never sampled
+)
+
+// Window is the liveness record kept for one tmux window.
+type Window struct {
+	Session       string    `json:"session"`
+	WindowIndex   int       `json:"window_index"`
+	WindowName    string    `json:"window_name"`
+	LastOutputAt  time.Time `json:"last_output_at"`
+	LastSampledAt time.Time `json:"last_sampled_at"`
+	LastSnapshot  string    `json:"last_snapshot"`
+}
+
+// State derives a State from w's timestamps: working if output changed
+// within workingWithin of now, waiting otherwise.
+func (w Window) State(workingWithin time.Duration) string {
+	if w.LastSampledAt.IsZero() {
+		return StateUnknown
+	}
+	if time.Since(w.LastOutputAt) <= workingWithin {
+		return StateWorking
+	}
+	return StateWaiting
+}
+
+// Stale reports whether w hasn't been sampled recently, i.e. no
+// heartbeat writer has observed it within maxAge.
+func (w Window) Stale(maxAge time.Duration) bool {
+	return w.LastSampledAt.IsZero() || time.Since(w.LastSampledAt) > maxAge
+}
+
+// Key is the state-file key for a window.
+func Key(session string, windowIndex int) string {
+	return fmt.Sprintf("%s:%d", session, windowIndex)
+}
+
+// DefaultPath returns $XDG_STATE_HOME/gasadd/state.json (or
+// ~/.local/state/gasadd/state.json).
+func DefaultPath() (string, error) {
+	var dir string
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		dir = filepath.Join(xdg, "gasadd")
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state", "gasadd")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating state dir: %w", err)
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// Store persists the liveness table to a single JSON file. It is not
+// safe for concurrent use from multiple processes beyond last-write-wins;
+// heartbeat sampling is a best-effort signal, not a source of truth.
+type Store struct {
+	path string
+}
+
+// Open returns a Store backed by path. The file is created on first Save.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the liveness table, returning an empty map if the state file
+// doesn't exist yet.
+func (s *Store) Load() (map[string]Window, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Window{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+	var m map[string]Window
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	return m, nil
+}
+
+// Save writes the liveness table back to disk.
+func (s *Store) Save(m map[string]Window) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state file: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	return nil
+}
+
+// Sample captures the current pane text for each of windows and updates
+// state in place: LastOutputAt only advances when the captured text
+// differs from the previous sample, so a window that's merely displaying
+// a static prompt doesn't look like it's perpetually working.
+func Sample(transport tmux.Transport, session string, windows []tmux.Window, state map[string]Window) {
+	now := time.Now()
+	for _, w := range windows {
+		key := Key(session, w.Index)
+		prev := state[key]
+		rec := Window{Session: session, WindowIndex: w.Index, WindowName: w.Name}
+
+		snap, err := transport.CapturePane(fmt.Sprintf("%s:%d", session, w.Index), 10)
+		if err != nil {
+			// The sample attempt failed, so this isn't a heartbeat: leave
+			// LastSampledAt (and everything else) as it was.
+			rec.LastSampledAt = prev.LastSampledAt
+			rec.LastOutputAt = prev.LastOutputAt
+			rec.LastSnapshot = prev.LastSnapshot
+			state[key] = rec
+			continue
+		}
+
+		rec.LastSampledAt = now
+		rec.LastSnapshot = snap
+		if prev.LastOutputAt.IsZero() || snap != prev.LastSnapshot {
+			rec.LastOutputAt = now
+		} else {
+			rec.LastOutputAt = prev.LastOutputAt
+		}
+		state[key] = rec
+	}
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowState(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name string
+		w    Window
+		want string
+	}{
+		{"never sampled", Window{}, StateUnknown},
+		{
+			"output just now",
+			Window{LastSampledAt: now, LastOutputAt: now},
+			StateWorking,
+		},
+		{
+			"output long ago",
+			Window{LastSampledAt: now, LastOutputAt: now.Add(-time.Hour)},
+			StateWaiting,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.w.State(10 * time.Second); got != c.want {
+				t.Errorf("State() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWindowStale(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name string
+		w    Window
+		want bool
+	}{
+		{"never sampled", Window{}, true},
+		{"sampled just now", Window{LastSampledAt: now}, false},
+		{"sampled long ago", Window{LastSampledAt: now.Add(-time.Hour)}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.w.Stale(time.Minute); got != c.want {
+				t.Errorf("Stale() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package lock provides the per-target advisory locking ga uses to
+// serialize concurrent senders (a human typing `ga` while a cron job also
+// fires `ga`) against the same tmux pane, mirroring how coder/cli wraps
+// SSH config mutations behind gofrs/flock.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// DefaultTimeout is used when callers don't have an explicit
+// --lock-timeout to pass.
+const DefaultTimeout = 15 * time.Second
+
+// pollInterval is how often TryLockContext retries while waiting for the
+// lock to free up.
+const pollInterval = 100 * time.Millisecond
+
+// Dir returns $XDG_RUNTIME_DIR/gasadd/locks (or, when XDG_RUNTIME_DIR
+// isn't set, ~/.local/state/gasadd/locks), creating it with 0700 if
+// necessary.
+func Dir() (string, error) {
+	var dir string
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		dir = filepath.Join(runtimeDir, "gasadd", "locks")
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state", "gasadd", "locks")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating lock dir: %w", err)
+	}
+	return dir, nil
+}
+
+// Target acquires the advisory lock for a single tmux target
+// (session:window), blocking up to timeout. Release the lock by calling
+// Unlock on the returned *flock.Flock once the retry-check-and-send
+// sequence against that target is done.
+func Target(session string, window int, timeout time.Duration) (*flock.Flock, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.lock", session, window))
+	fl := flock.New(path)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	locked, err := fl.TryLockContext(ctx, pollInterval)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("acquiring lock for %s:%d: %w", session, window, err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("timed out after %s waiting for lock on %s:%d (another ga/gn/gp invocation is targeting it)", timeout, session, window)
+	}
+	return fl, nil
+}
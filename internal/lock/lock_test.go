@@ -0,0 +1,33 @@
+package lock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTargetContention(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	fl, err := Target("session", 3, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("first Target: %v", err)
+	}
+
+	if _, err := Target("session", 3, 200*time.Millisecond); err == nil {
+		t.Fatal("second Target on the same session:window succeeded while the first held the lock")
+	}
+
+	if _, err := Target("session", 4, 200*time.Millisecond); err != nil {
+		t.Fatalf("Target on a different window should not contend: %v", err)
+	}
+
+	if err := fl.Unlock(); err != nil {
+		t.Fatalf("unlocking first Target: %v", err)
+	}
+
+	if fl2, err := Target("session", 3, 200*time.Millisecond); err != nil {
+		t.Fatalf("Target after the first was released: %v", err)
+	} else {
+		fl2.Unlock()
+	}
+}
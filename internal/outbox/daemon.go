@@ -0,0 +1,202 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nmelo/gasadd/internal/audit"
+	"github.com/nmelo/gasadd/internal/lock"
+	"github.com/nmelo/gasadd/internal/tmux"
+)
+
+// Daemon watches the outbox and delivers queued messages as soon as each
+// target's pane goes idle, and serves the control socket the CLI uses to
+// push new items and query status.
+type Daemon struct {
+	Store       *Store
+	Transport   tmux.Transport
+	MaxAge      time.Duration // items older than this are marked expired instead of retried
+	PollEvery   time.Duration
+	LockTimeout time.Duration     // how long to wait for a target's advisory lock; defaults to lock.DefaultTimeout
+	Logger      *audit.FileLogger // optional; records each delivery attempt so the audit log doesn't lose outbox items the moment runAdd hands them off
+
+	listener net.Listener
+}
+
+// Run opens the control socket and blocks, polling the outbox and serving
+// requests until the process is killed or an unrecoverable error occurs.
+func (d *Daemon) Run() error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	_ = os.Remove(path) // clear a stale socket from a previous crash
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", path, err)
+	}
+	defer l.Close()
+	defer os.Remove(path)
+	d.listener = l
+
+	if d.PollEvery == 0 {
+		d.PollEvery = 5 * time.Second
+	}
+	if d.LockTimeout == 0 {
+		d.LockTimeout = lock.DefaultTimeout
+	}
+
+	go d.pollLoop()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return fmt.Errorf("accept: %w", err)
+		}
+		go d.handleConn(conn)
+	}
+}
+
+func (d *Daemon) pollLoop() {
+	ticker := time.NewTicker(d.PollEvery)
+	defer ticker.Stop()
+	for range ticker.C {
+		d.deliverDue()
+	}
+}
+
+// deliverDue attempts delivery of every pending item whose NextAttempt
+// has arrived.
+func (d *Daemon) deliverDue() {
+	d.deliver(false)
+}
+
+// deliver attempts delivery of pending items; force bypasses the
+// NextAttempt check (used by `ga outbox flush`).
+func (d *Daemon) deliver(force bool) {
+	items, err := d.Store.List(StatusPending)
+	if err != nil {
+		log.Printf("outbox: listing pending items: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, it := range items {
+		if !force && it.NextAttempt.After(now) {
+			continue
+		}
+		d.deliverOne(it)
+	}
+}
+
+// deliverOne runs the check-and-send sequence for a single item under
+// that target's advisory lock, mirroring runAdd's sendOne so a human
+// `ga` and the daemon can never interleave keystrokes into the same pane.
+func (d *Daemon) deliverOne(it Item) {
+	fl, err := lock.Target(it.Session, it.Window, d.LockTimeout)
+	if err != nil {
+		log.Printf("outbox: locking %s: %v", it.Target, err)
+		return
+	}
+	defer fl.Unlock()
+
+	hasPending, err := d.Transport.HasPendingInput(it.Target)
+	if err != nil {
+		log.Printf("outbox: checking %s: %v", it.Target, err)
+		return
+	}
+	if hasPending {
+		d.logDelivery(it, audit.ActionDeferred, true, "")
+		if err := d.Store.MarkAttempt(it.ID, false, d.MaxAge); err != nil {
+			log.Printf("outbox: recording attempt for %d: %v", it.ID, err)
+		}
+		return
+	}
+	if err := d.Transport.AddMessage(it.Target, it.Message); err != nil {
+		log.Printf("outbox: delivering %d to %s: %v", it.ID, it.Target, err)
+		d.logDelivery(it, audit.ActionFailed, false, err.Error())
+		if mErr := d.Store.MarkAttempt(it.ID, false, d.MaxAge); mErr != nil {
+			log.Printf("outbox: recording attempt for %d: %v", it.ID, mErr)
+		}
+		return
+	}
+	d.logDelivery(it, audit.ActionQueued, false, "")
+	if err := d.Store.MarkAttempt(it.ID, true, d.MaxAge); err != nil {
+		log.Printf("outbox: marking %d delivered: %v", it.ID, err)
+	}
+}
+
+// logDelivery appends a Record for this delivery attempt to the audit
+// log, if one is configured, so a message handed to the outbox stays
+// visible in the log instead of disappearing the moment runAdd defers it.
+func (d *Daemon) logDelivery(it Item, action string, pendingInput bool, errMsg string) {
+	if d.Logger == nil {
+		return
+	}
+	rec := audit.Record{
+		Timestamp:     time.Now(),
+		Session:       it.Session,
+		WindowIndex:   it.Window,
+		Action:        action,
+		PendingInput:  pendingInput,
+		Error:         errMsg,
+		Attempts:      it.Attempts,
+		MessageSHA256: audit.MessageHash(it.Message),
+	}
+	if err := d.Logger.Append(rec); err != nil {
+		log.Printf("outbox: writing audit log: %v", err)
+	}
+}
+
+func (d *Daemon) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	resp := d.handle(req)
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func (d *Daemon) handle(req Request) Response {
+	switch req.Op {
+	case "ping":
+		return Response{OK: true}
+
+	case "enqueue":
+		if _, err := d.Store.Enqueue(req.Target, req.Session, req.Window, req.Message); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true}
+
+	case "list":
+		items, err := d.Store.List(req.Message) // Message doubles as the status filter for this op
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true, Items: items}
+
+	case "cancel":
+		if err := d.Store.Cancel(req.ID); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true}
+
+	case "flush":
+		d.deliver(true)
+		return Response{OK: true}
+
+	default:
+		return Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+}
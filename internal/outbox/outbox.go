@@ -0,0 +1,226 @@
+// Package outbox implements gasadd's persistent outbox: messages that
+// couldn't be delivered immediately (the target pane had pending input)
+// are recorded to disk and retried by the `ga daemon` process once the
+// target goes idle, instead of being dropped.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Status values an Item can hold.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+var itemsBucket = []byte("items")
+
+// Item is a single queued message awaiting delivery.
+type Item struct {
+	ID          uint64    `json:"id"`
+	Target      string    `json:"target"`  // e.g. "session:3"
+	Session     string    `json:"session"` // also stored standalone for health/reporting
+	Window      int       `json:"window"`
+	Message     string    `json:"message"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	Attempts    int       `json:"attempts"`
+	Status      string    `json:"status"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// backoff returns the delay before the next delivery attempt after
+// `attempts` prior tries: 5s, 10s, 20s, ... capped at 5 minutes.
+func backoff(attempts int) time.Duration {
+	const base = 5 * time.Second
+	const maxBackoff = 5 * time.Minute
+	if attempts <= 1 {
+		return base
+	}
+	d := base << (attempts - 1)
+	if d <= 0 || d > maxBackoff { // overflow or past cap
+		return maxBackoff
+	}
+	return d
+}
+
+// Store is the persistent outbox backed by a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns $XDG_STATE_HOME/gasadd/outbox.db (or
+// ~/.local/state/gasadd/outbox.db).
+func DefaultPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "outbox.db"), nil
+}
+
+// StateDir returns $XDG_STATE_HOME/gasadd (or ~/.local/state/gasadd),
+// creating it if necessary.
+func StateDir() (string, error) {
+	var dir string
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		dir = filepath.Join(xdg, "gasadd")
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state", "gasadd")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating state dir: %w", err)
+	}
+	return dir, nil
+}
+
+// Open opens (creating if necessary) the outbox database at path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating outbox dir: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening outbox db: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(itemsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Enqueue records a new pending item and returns it with its assigned ID.
+func (s *Store) Enqueue(target, session string, window int, message string) (Item, error) {
+	var item Item
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(itemsBucket)
+		id, _ := b.NextSequence()
+		item = Item{
+			ID:          id,
+			Target:      target,
+			Session:     session,
+			Window:      window,
+			Message:     message,
+			EnqueuedAt:  time.Now(),
+			Status:      StatusPending,
+			NextAttempt: time.Now(),
+		}
+		return putItem(b, item)
+	})
+	return item, err
+}
+
+// List returns every item in the outbox, optionally filtered to a single
+// status (pass "" for all).
+func (s *Store) List(status string) ([]Item, error) {
+	var items []Item
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(itemsBucket)
+		return b.ForEach(func(_, v []byte) error {
+			var it Item
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			if status == "" || it.Status == status {
+				items = append(items, it)
+			}
+			return nil
+		})
+	})
+	return items, err
+}
+
+// Get returns a single item by ID.
+func (s *Store) Get(id uint64) (Item, error) {
+	var item Item
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(itemsBucket).Get(idKey(id))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &item)
+	})
+	if err == nil && !found {
+		return Item{}, fmt.Errorf("no outbox item with id %d", id)
+	}
+	return item, err
+}
+
+// MarkAttempt increments an item's attempt count and, on success,
+// transitions it to delivered; on failure it stays pending for the next
+// retry unless maxAge has elapsed, in which case it expires.
+func (s *Store) MarkAttempt(id uint64, delivered bool, maxAge time.Duration) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(itemsBucket)
+		v := b.Get(idKey(id))
+		if v == nil {
+			return fmt.Errorf("no outbox item with id %d", id)
+		}
+		var it Item
+		if err := json.Unmarshal(v, &it); err != nil {
+			return err
+		}
+		it.Attempts++
+		switch {
+		case delivered:
+			it.Status = StatusDelivered
+		case maxAge > 0 && time.Since(it.EnqueuedAt) > maxAge:
+			it.Status = StatusExpired
+		default:
+			it.NextAttempt = time.Now().Add(backoff(it.Attempts))
+		}
+		return putItem(b, it)
+	})
+}
+
+// Cancel marks an item as cancelled so the daemon skips it.
+func (s *Store) Cancel(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(itemsBucket)
+		v := b.Get(idKey(id))
+		if v == nil {
+			return fmt.Errorf("no outbox item with id %d", id)
+		}
+		var it Item
+		if err := json.Unmarshal(v, &it); err != nil {
+			return err
+		}
+		it.Status = StatusCancelled
+		return putItem(b, it)
+	})
+}
+
+func putItem(b *bolt.Bucket, it Item) error {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	return b.Put(idKey(it.ID), data)
+}
+
+func idKey(id uint64) []byte {
+	return []byte(fmt.Sprintf("%020d", id))
+}
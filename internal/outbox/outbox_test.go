@@ -0,0 +1,26 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{4, 40 * time.Second},
+		{10, 5 * time.Minute}, // well past the cap
+		{100, 5 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := backoff(c.attempts); got != c.want {
+			t.Errorf("backoff(%d) = %s, want %s", c.attempts, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SocketPath returns $XDG_RUNTIME_DIR/gasadd.sock, the unix socket the
+// daemon listens on and the CLI connects to for pushing new items and
+// querying status.
+func SocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gasadd.sock"), nil
+	}
+	return "", fmt.Errorf("XDG_RUNTIME_DIR is not set; cannot locate gasadd daemon socket")
+}
+
+// Request is a single line of newline-delimited JSON sent over the
+// daemon's control socket.
+type Request struct {
+	Op      string `json:"op"` // "enqueue", "list", "cancel", "flush", "ping"
+	Target  string `json:"target,omitempty"`
+	Session string `json:"session,omitempty"`
+	Window  int    `json:"window,omitempty"`
+	Message string `json:"message,omitempty"`
+	ID      uint64 `json:"id,omitempty"`
+}
+
+// Response is the daemon's reply to a Request.
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	Items []Item `json:"items,omitempty"`
+}
+
+// Ping reports whether a daemon is listening on SocketPath. It is used by
+// runAdd to decide whether to enqueue to the outbox or fall back to
+// today's skip-and-warn behavior.
+func Ping() bool {
+	path, err := SocketPath()
+	if err != nil {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	resp, err := roundTrip(conn, Request{Op: "ping"})
+	return err == nil && resp.OK
+}
+
+// SendEnqueue asks the running daemon to enqueue message for delivery to
+// target once it goes idle.
+func SendEnqueue(target, session string, window int, message string) error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return fmt.Errorf("connecting to gasadd daemon: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := roundTrip(conn, Request{Op: "enqueue", Target: target, Session: session, Window: window, Message: message})
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("daemon rejected enqueue: %s", resp.Error)
+	}
+	return nil
+}
+
+// QueryList asks the daemon for the current contents of the outbox,
+// optionally filtered to a single status ("" for all).
+func QueryList(status string) ([]Item, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to gasadd daemon: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := roundTrip(conn, Request{Op: "list", Message: status})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("daemon rejected list: %s", resp.Error)
+	}
+	return resp.Items, nil
+}
+
+// SendCancel asks the daemon to cancel a pending item by ID.
+func SendCancel(id uint64) error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return fmt.Errorf("connecting to gasadd daemon: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := roundTrip(conn, Request{Op: "cancel", ID: id})
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("daemon rejected cancel: %s", resp.Error)
+	}
+	return nil
+}
+
+// SendFlush asks the daemon to retry delivery of every pending item right
+// now, ignoring each item's backoff schedule.
+func SendFlush() error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return fmt.Errorf("connecting to gasadd daemon: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := roundTrip(conn, Request{Op: "flush"})
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("daemon rejected flush: %s", resp.Error)
+	}
+	return nil
+}
+
+func roundTrip(conn net.Conn, req Request) (Response, error) {
+	enc := json.NewEncoder(conn)
+	if err := enc.Encode(req); err != nil {
+		return Response{}, fmt.Errorf("writing request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("reading response: %w", err)
+	}
+	return resp, nil
+}
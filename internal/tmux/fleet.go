@@ -0,0 +1,54 @@
+package tmux
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Fleet is a named group of hosts that ga can fan a message out to in one
+// invocation, e.g. `ga -F mycluster "message"`.
+type Fleet struct {
+	Hosts []HostSpec `json:"hosts"`
+}
+
+// fleetFile is the on-disk shape of the fleets config: a map of fleet name
+// to its member hosts.
+type fleetFile struct {
+	Fleets map[string]Fleet `json:"fleets"`
+}
+
+// FleetsConfigPath returns the default location of the fleets config file,
+// $XDG_CONFIG_HOME/gasadd/fleets.json (or ~/.config/gasadd/fleets.json).
+func FleetsConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gasadd", "fleets.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gasadd", "fleets.json"), nil
+}
+
+// LoadFleet reads the fleets config and returns the named fleet.
+func LoadFleet(name string) (Fleet, error) {
+	path, err := FleetsConfigPath()
+	if err != nil {
+		return Fleet{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fleet{}, fmt.Errorf("reading fleets config %s: %w", path, err)
+	}
+	var cfg fleetFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Fleet{}, fmt.Errorf("parsing fleets config %s: %w", path, err)
+	}
+	fleet, ok := cfg.Fleets[name]
+	if !ok {
+		return Fleet{}, fmt.Errorf("no fleet named %q in %s", name, path)
+	}
+	return fleet, nil
+}
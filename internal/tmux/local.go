@@ -0,0 +1,152 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// LocalTransport implements Transport against the tmux server running on
+// the local machine via exec.Command("tmux", ...).
+type LocalTransport struct{}
+
+// NewLocalTransport returns a Transport backed by the local tmux binary.
+func NewLocalTransport() *LocalTransport {
+	return &LocalTransport{}
+}
+
+// ListSessions returns the names of every tmux session on the local
+// server. Like IsInsideTmux and GetCurrentContext, this only makes sense
+// locally, so it's a free function rather than part of Transport.
+func ListSessions() ([]string, error) {
+	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	if err != nil {
+		// No server running yet is not an error worth propagating.
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tmux list-sessions: %w", err)
+	}
+	var sessions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+	return sessions, nil
+}
+
+func (LocalTransport) SessionExists(session string) bool {
+	err := exec.Command("tmux", "has-session", "-t", session).Run()
+	return err == nil
+}
+
+func (LocalTransport) ListWindows(session string) ([]Window, error) {
+	out, err := exec.Command("tmux", "list-windows", "-t", session, "-F",
+		"#{window_index}\t#{window_name}\t#{pane_current_command}\t#{pane_id}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("tmux list-windows: %w", err)
+	}
+	return parseWindows(out)
+}
+
+func (LocalTransport) HasPendingInput(target string) (bool, error) {
+	return hasPendingInput(target)
+}
+
+func (LocalTransport) AddMessage(target, message string) error {
+	if err := exec.Command("tmux", "send-keys", "-t", target, message).Run(); err != nil {
+		return fmt.Errorf("tmux send-keys: %w", err)
+	}
+	if err := exec.Command("tmux", "send-keys", "-t", target, "Enter").Run(); err != nil {
+		return fmt.Errorf("tmux send-keys Enter: %w", err)
+	}
+	return nil
+}
+
+func (LocalTransport) CapturePane(target string, lines int) (string, error) {
+	args := []string{"capture-pane", "-p", "-t", target}
+	if lines > 0 {
+		args = append(args, "-S", "-"+strconv.Itoa(lines))
+	}
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux capture-pane: %w", err)
+	}
+	return string(out), nil
+}
+
+func (LocalTransport) ChildProcesses(paneID string) []string {
+	if paneID == "" {
+		return nil
+	}
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", paneID, "#{pane_pid}").Output()
+	if err != nil {
+		return nil
+	}
+	pid := strings.TrimSpace(string(out))
+	if pid == "" {
+		return nil
+	}
+	children, err := exec.Command("pgrep", "-P", pid).Output()
+	if err != nil {
+		return nil
+	}
+	var comms []string
+	for _, line := range strings.Fields(string(children)) {
+		commOut, err := exec.Command("ps", "-p", line, "-o", "comm=").Output()
+		if err != nil {
+			continue
+		}
+		comms = append(comms, strings.ToLower(strings.TrimSpace(string(commOut))))
+	}
+	return comms
+}
+
+// parseWindows parses the tab-delimited output of list-windows shared by
+// the local and SSH transports (both run the same format string).
+func parseWindows(out []byte) ([]Window, error) {
+	var windows []Window
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		idx, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		w := Window{Index: idx, Name: fields[1], Command: fields[2]}
+		if len(fields) > 3 {
+			w.PaneID = fields[3]
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// hasPendingInput reports whether the pane's current input line differs
+// from an empty prompt, i.e. the user has typed something that hasn't
+// been submitted yet. It's a cheap heuristic: a non-empty trailing line
+// beyond the prompt marker counts as pending input.
+func hasPendingInput(target string) (bool, error) {
+	out, err := exec.Command("tmux", "capture-pane", "-p", "-t", target).Output()
+	if err != nil {
+		return false, fmt.Errorf("tmux capture-pane: %w", err)
+	}
+	lines := bytes.Split(bytes.TrimRight(out, "\n"), []byte("\n"))
+	if len(lines) == 0 {
+		return false, nil
+	}
+	last := bytes.TrimSpace(lines[len(lines)-1])
+	// A bare prompt marker ("│ >" in Claude's box UI) with nothing after
+	// it means the user isn't mid-keystroke. Only "│", ">" and spaces are
+	// stripped, so any other prompt text counts as pending input.
+	trimmed := bytes.TrimLeft(last, "│> ")
+	return len(trimmed) > 0, nil
+}
@@ -0,0 +1,294 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostSpec identifies a remote host to run tmux commands against over SSH.
+type HostSpec struct {
+	// Host is user@host[:port], same syntax as ssh(1).
+	Host string
+	// Identity is an optional path to a private key file. When empty,
+	// SSHTransport falls back to SSH_AUTH_SOCK.
+	Identity string
+	// Jump is an optional ProxyJump host (user@host[:port]).
+	Jump string
+}
+
+// SSHTransport implements Transport by running the same tmux commands
+// LocalTransport runs, over an SSH session on a remote host. One
+// ssh.Client is dialed per SSHTransport and reused across calls.
+type SSHTransport struct {
+	spec   HostSpec
+	client *ssh.Client
+}
+
+// NewSSHTransport dials spec.Host (optionally via spec.Jump) and returns a
+// Transport that runs tmux commands there.
+func NewSSHTransport(spec HostSpec) (*SSHTransport, error) {
+	client, err := dial(spec)
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", spec.Host, err)
+	}
+	return &SSHTransport{spec: spec, client: client}, nil
+}
+
+// Close releases the underlying SSH connection (and jump connection, if
+// any).
+func (t *SSHTransport) Close() error {
+	if t.client == nil {
+		return nil
+	}
+	return t.client.Close()
+}
+
+func dial(spec HostSpec) (*ssh.Client, error) {
+	user, addr, err := splitUserHost(spec.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientConfig(user, spec.Identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Jump == "" {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	jumpUser, jumpAddr, err := splitUserHost(spec.Jump)
+	if err != nil {
+		return nil, fmt.Errorf("parsing jump host: %w", err)
+	}
+	jumpConfig, err := clientConfig(jumpUser, spec.Identity)
+	if err != nil {
+		return nil, err
+	}
+	jumpClient, err := ssh.Dial("tcp", jumpAddr, jumpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("dialing jump host %s: %w", spec.Jump, err)
+	}
+	conn, err := jumpClient.Dial("tcp", addr)
+	if err != nil {
+		jumpClient.Close()
+		return nil, fmt.Errorf("dialing %s via jump host: %w", addr, err)
+	}
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		jumpClient.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(ncc, chans, reqs)
+	go func() {
+		client.Wait()
+		jumpClient.Close()
+	}()
+	return client, nil
+}
+
+func splitUserHost(spec string) (user, addr string, err error) {
+	user = os.Getenv("USER")
+	host := spec
+	if i := strings.Index(spec, "@"); i != -1 {
+		user = spec[:i]
+		host = spec[i+1:]
+	}
+	if user == "" {
+		return "", "", fmt.Errorf("no user specified and $USER is empty in %q", spec)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	return user, host, nil
+}
+
+func clientConfig(user, identity string) (*ssh.ClientConfig, error) {
+	hostKeyCallback, err := defaultHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	auths, err := authMethods(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            auths,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	}, nil
+}
+
+func defaultHostKeyCallback() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+}
+
+func authMethods(identity string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if identity != "" {
+		key, err := os.ReadFile(identity)
+		if err != nil {
+			return nil, fmt.Errorf("reading identity file: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("parsing identity file: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no SSH auth methods available (set --identity or SSH_AUTH_SOCK)")
+	}
+	return methods, nil
+}
+
+// run executes a tmux subcommand on the remote host and returns its
+// stdout. Arguments are shell-quoted individually so window names and
+// messages containing spaces survive the trip.
+func (t *SSHTransport) run(args ...string) ([]byte, error) {
+	session, err := t.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("opening ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	cmd := "tmux " + strings.Join(quoted, " ")
+
+	if err := session.Run(cmd); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", cmd, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runRaw executes an arbitrary (non-tmux-prefixed) command line on the
+// remote host, used for process-tree inspection (pgrep/ps) that has
+// nothing to do with tmux itself.
+func (t *SSHTransport) runRaw(cmd string) ([]byte, error) {
+	session, err := t.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("opening ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	if err := session.Run(cmd); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", cmd, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+func (t *SSHTransport) SessionExists(session string) bool {
+	_, err := t.run("has-session", "-t", session)
+	return err == nil
+}
+
+func (t *SSHTransport) ListWindows(session string) ([]Window, error) {
+	out, err := t.run("list-windows", "-t", session, "-F",
+		"#{window_index}\t#{window_name}\t#{pane_current_command}\t#{pane_id}")
+	if err != nil {
+		return nil, err
+	}
+	return parseWindows(out)
+}
+
+func (t *SSHTransport) HasPendingInput(target string) (bool, error) {
+	out, err := t.run("capture-pane", "-p", "-t", target)
+	if err != nil {
+		return false, err
+	}
+	lines := bytes.Split(bytes.TrimRight(out, "\n"), []byte("\n"))
+	if len(lines) == 0 {
+		return false, nil
+	}
+	last := bytes.TrimSpace(lines[len(lines)-1])
+	trimmed := bytes.TrimLeft(last, "│> ")
+	return len(trimmed) > 0, nil
+}
+
+func (t *SSHTransport) AddMessage(target, message string) error {
+	if _, err := t.run("send-keys", "-t", target, message); err != nil {
+		return err
+	}
+	_, err := t.run("send-keys", "-t", target, "Enter")
+	return err
+}
+
+func (t *SSHTransport) CapturePane(target string, lines int) (string, error) {
+	args := []string{"capture-pane", "-p", "-t", target}
+	if lines > 0 {
+		args = append(args, "-S", "-"+strconv.Itoa(lines))
+	}
+	out, err := t.run(args...)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (t *SSHTransport) ChildProcesses(paneID string) []string {
+	if paneID == "" {
+		return nil
+	}
+	out, err := t.run("display-message", "-p", "-t", paneID, "#{pane_pid}")
+	if err != nil {
+		return nil
+	}
+	pid := strings.TrimSpace(string(out))
+	if pid == "" {
+		return nil
+	}
+	children, err := t.runRaw("pgrep -P " + shellQuote(pid))
+	if err != nil {
+		return nil
+	}
+	var comms []string
+	for _, line := range strings.Fields(string(children)) {
+		commOut, err := t.runRaw("ps -p " + shellQuote(line) + " -o comm=")
+		if err != nil {
+			continue
+		}
+		comms = append(comms, strings.ToLower(strings.TrimSpace(string(commOut))))
+	}
+	return comms
+}
@@ -0,0 +1,152 @@
+// Package tmux provides the primitives ga (and its sibling tools gn/gp/gm)
+// use to discover tmux windows, detect Claude agents running inside them,
+// and deliver messages without disturbing whatever the user is typing.
+//
+// Operations are expressed through the Transport interface so the same
+// window-filtering and delivery logic in cmd can run against tmux on the
+// local machine or on a remote host reached over SSH.
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Window describes a single tmux window as reported by list-windows.
+type Window struct {
+	Index   int
+	Name    string
+	Command string
+	PaneID  string
+}
+
+// Transport abstracts the tmux operations ga needs against a tmux server,
+// whether that server is running on the local machine or on a remote host
+// reached over SSH. Implementations: LocalTransport, SSHTransport.
+type Transport interface {
+	// SessionExists reports whether the named session exists.
+	SessionExists(session string) bool
+
+	// ListWindows returns every window in the given session.
+	ListWindows(session string) ([]Window, error)
+
+	// HasPendingInput reports whether the target pane has unsent input in
+	// its edit buffer (i.e. the user is mid-keystroke).
+	HasPendingInput(target string) (bool, error)
+
+	// AddMessage sends message text followed by Enter to the target pane.
+	AddMessage(target, message string) error
+
+	// CapturePane returns the target pane's visible contents preceded by
+	// up to `lines` lines of scrollback history, or just the visible pane
+	// if lines <= 0.
+	CapturePane(target string, lines int) (string, error)
+
+	// ChildProcesses returns the lowercased comm names of the direct
+	// child processes of the shell running in the pane at paneID, used
+	// by IsClaudeRunning to detect a claude/node process started inside
+	// a shell. Best-effort: returns nil (not an error) when the process
+	// tree can't be inspected.
+	ChildProcesses(paneID string) []string
+}
+
+// IsInsideTmux reports whether the current process is running inside a
+// tmux session.
+func IsInsideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// GetCurrentContext returns the session name, window index, and pane ID of
+// the tmux pane the current process is attached to. It only makes sense to
+// call this for the local tmux server, which is why it lives outside the
+// Transport interface.
+func GetCurrentContext() (session string, windowIndex int, paneID string, err error) {
+	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}\t#{window_index}\t#{pane_id}").Output()
+	if err != nil {
+		return "", 0, "", fmt.Errorf("tmux display-message: %w", err)
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(fields) != 3 {
+		return "", 0, "", fmt.Errorf("unexpected display-message output: %q", out)
+	}
+	idx, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, "", fmt.Errorf("parsing window index: %w", err)
+	}
+	return fields[0], idx, fields[2], nil
+}
+
+// MatchPattern reports whether name matches the glob-style pattern (the
+// same syntax as filepath.Match).
+func MatchPattern(name, pattern string) bool {
+	ok, err := filepath.Match(pattern, name)
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
+// IsClaudeRunning reports whether w looks like it is running a Claude
+// agent, based on pane_current_command matching "claude", "node", a bare
+// version string, or a shell whose child process is one of those. The
+// shell-child check runs against transport, so it works for windows
+// discovered on a remote host (SSHTransport) and not just the local tmux
+// server.
+func IsClaudeRunning(transport Transport, w Window) bool {
+	cmd := strings.ToLower(w.Command)
+	if cmd == "claude" || cmd == "node" {
+		return true
+	}
+	if isVersionString(cmd) {
+		return true
+	}
+	if isShell(cmd) {
+		return shellHasClaudeChild(transport, w.PaneID)
+	}
+	return false
+}
+
+func isVersionString(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+		for _, r := range p {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isShell(cmd string) bool {
+	switch cmd {
+	case "bash", "zsh", "sh", "fish":
+		return true
+	}
+	return false
+}
+
+// shellHasClaudeChild inspects the process tree under the pane's shell
+// (via transport) for a claude/node child process. It is best-effort:
+// failures are treated as "no Claude child".
+func shellHasClaudeChild(transport Transport, paneID string) bool {
+	if paneID == "" {
+		return false
+	}
+	for _, comm := range transport.ChildProcesses(paneID) {
+		if comm == "claude" || comm == "node" || isVersionString(comm) {
+			return true
+		}
+	}
+	return false
+}